@@ -0,0 +1,171 @@
+package linter
+
+import (
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Severity is how serious a rule's findings are reported as.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// RuleSettings controls whether a single rule runs and how severe its
+// findings are. Enabled is a *bool (rather than bool) so that setting only
+// Severity in a linter.yaml or a test case's Config doesn't also silently
+// disable the rule by leaving Enabled at its bool zero value.
+type RuleSettings struct {
+	Enabled  *bool    `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+	Severity Severity `yaml:"severity,omitempty" json:"severity,omitempty"`
+}
+
+// SensitiveValueSettings tunes the sensitive-value rule's detectors beyond
+// the built-in AWS region / account-id / ARN / secret-keyword patterns.
+type SensitiveValueSettings struct {
+	RegionAllowlist     []string `yaml:"region-allowlist,omitempty" json:"region_allowlist,omitempty"`
+	ExtraPatterns       []string `yaml:"extra-patterns,omitempty" json:"extra_patterns,omitempty"`
+	ExtraSecretKeywords []string `yaml:"extra-secret-keywords,omitempty" json:"extra_secret_keywords,omitempty"`
+}
+
+// ScoringSettings tunes how the test harness scores the LLM's warnings
+// against the rule engine's: the weight given to a path match, a key
+// match and a message-similarity score, and the total score a warning
+// pair must clear to count as a match. The weights are *float64 (rather
+// than float64) so an explicit 0 in a linter.yaml or a test case's Config
+// is distinguishable from "not set" and actually takes effect.
+type ScoringSettings struct {
+	PathWeight     *float64 `yaml:"path-weight,omitempty" json:"path_weight,omitempty"`
+	KeyWeight      *float64 `yaml:"key-weight,omitempty" json:"key_weight,omitempty"`
+	MessageWeight  *float64 `yaml:"message-weight,omitempty" json:"message_weight,omitempty"`
+	MatchThreshold *float64 `yaml:"match-threshold,omitempty" json:"match_threshold,omitempty"`
+	// EmbeddingModel is the Ollama model used to embed messages for
+	// similarity scoring, e.g. "nomic-embed-text".
+	EmbeddingModel string `yaml:"embedding-model,omitempty" json:"embedding_model,omitempty"`
+}
+
+// Config is the tunable rule configuration read from a linter.yaml (or
+// .linterrc) file. The rule engine and the LLM prompt builder both read
+// from the same Config so their behavior stays in sync.
+type Config struct {
+	Rules          map[string]RuleSettings `yaml:"rules" json:"rules"`
+	SensitiveValue SensitiveValueSettings  `yaml:"sensitive-value" json:"sensitive_value"`
+	Scoring        ScoringSettings         `yaml:"scoring" json:"scoring"`
+}
+
+func boolPtr(b bool) *bool        { return &b }
+func floatPtr(f float64) *float64 { return &f }
+
+// DefaultConfig is the behavior the linter had before linter.yaml existed:
+// every built-in rule enabled, sensitive-value at error severity and
+// everything else at warning.
+func DefaultConfig() Config {
+	return Config{
+		Rules: map[string]RuleSettings{
+			RuleDuplicateKey:      {Enabled: boolPtr(true), Severity: SeverityWarning},
+			RuleRedundantOverride: {Enabled: boolPtr(true), Severity: SeverityWarning},
+			RuleOverrideOnlyKey:   {Enabled: boolPtr(true), Severity: SeverityWarning},
+			RuleSensitiveValue:    {Enabled: boolPtr(true), Severity: SeverityError},
+		},
+		Scoring: ScoringSettings{
+			PathWeight:     floatPtr(0.4),
+			KeyWeight:      floatPtr(0.4),
+			MessageWeight:  floatPtr(0.2),
+			MatchThreshold: floatPtr(0.75),
+			EmbeddingModel: "nomic-embed-text",
+		},
+	}
+}
+
+// LoadConfig reads a linter.yaml/.linterrc file at path and merges it onto
+// DefaultConfig() the same way MergeOverrides merges a test case's Config,
+// so the two configuration sources behave identically. Rules it doesn't
+// mention keep their default settings.
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+
+	var overrides Config
+	if err := yaml.Unmarshal(data, &overrides); err != nil {
+		return Config{}, err
+	}
+	return MergeOverrides(DefaultConfig(), overrides), nil
+}
+
+// MergeOverrides applies overrides onto base field-by-field and returns the
+// result: a rule entry in overrides.Rules merges its Enabled/Severity onto
+// base's entry for that rule (rather than replacing it wholesale, which
+// would zero out whichever of the two the override didn't set), a
+// non-empty SensitiveValue slice replaces base's, and a non-nil Scoring
+// pointer replaces base's. Fields overrides leaves unset keep base's value
+// instead of zeroing it out. This is how a linter.yaml or a test
+// fixture's partial Config (e.g. one that only disables a rule) should be
+// combined with DefaultConfig(), rather than replacing it wholesale.
+func MergeOverrides(base, overrides Config) Config {
+	cfg := base
+	cfg.Rules = make(map[string]RuleSettings, len(base.Rules))
+	for rule, settings := range base.Rules {
+		cfg.Rules[rule] = settings
+	}
+	for rule, override := range overrides.Rules {
+		settings, ok := cfg.Rules[rule]
+		if !ok {
+			settings = RuleSettings{Enabled: boolPtr(true), Severity: SeverityWarning}
+		}
+		if override.Enabled != nil {
+			settings.Enabled = override.Enabled
+		}
+		if override.Severity != "" {
+			settings.Severity = override.Severity
+		}
+		cfg.Rules[rule] = settings
+	}
+
+	if len(overrides.SensitiveValue.RegionAllowlist) > 0 {
+		cfg.SensitiveValue.RegionAllowlist = overrides.SensitiveValue.RegionAllowlist
+	}
+	if len(overrides.SensitiveValue.ExtraPatterns) > 0 {
+		cfg.SensitiveValue.ExtraPatterns = overrides.SensitiveValue.ExtraPatterns
+	}
+	if len(overrides.SensitiveValue.ExtraSecretKeywords) > 0 {
+		cfg.SensitiveValue.ExtraSecretKeywords = overrides.SensitiveValue.ExtraSecretKeywords
+	}
+
+	if overrides.Scoring.PathWeight != nil {
+		cfg.Scoring.PathWeight = overrides.Scoring.PathWeight
+	}
+	if overrides.Scoring.KeyWeight != nil {
+		cfg.Scoring.KeyWeight = overrides.Scoring.KeyWeight
+	}
+	if overrides.Scoring.MessageWeight != nil {
+		cfg.Scoring.MessageWeight = overrides.Scoring.MessageWeight
+	}
+	if overrides.Scoring.MatchThreshold != nil {
+		cfg.Scoring.MatchThreshold = overrides.Scoring.MatchThreshold
+	}
+	if overrides.Scoring.EmbeddingModel != "" {
+		cfg.Scoring.EmbeddingModel = overrides.Scoring.EmbeddingModel
+	}
+	return cfg
+}
+
+func (c Config) Enabled(rule string) bool {
+	settings, ok := c.Rules[rule]
+	if !ok || settings.Enabled == nil {
+		return true
+	}
+	return *settings.Enabled
+}
+
+func (c Config) Severity(rule string) Severity {
+	if settings, ok := c.Rules[rule]; ok && settings.Severity != "" {
+		return settings.Severity
+	}
+	return SeverityWarning
+}
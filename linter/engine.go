@@ -0,0 +1,134 @@
+package linter
+
+import (
+	"sort"
+	"strings"
+)
+
+// Rule names, used to identify which check produced a given warning.
+const (
+	RuleDuplicateKey      = "duplicate-key"
+	RuleRedundantOverride = "redundant-override"
+	RuleOverrideOnlyKey   = "override-only-key"
+	RuleSensitiveValue    = "sensitive-value"
+)
+
+// fileNode wraps a FileData with its directory segments below the chart
+// root, which is all the context the rules below need to reconstruct the
+// override hierarchy described in the TestLinterAccuracy prompt.
+type fileNode struct {
+	file FileData
+	dirs []string
+}
+
+func fileNodes(tree ConfigTree) []fileNode {
+	nodes := make([]fileNode, 0, len(tree.Configs))
+	for _, f := range tree.Configs {
+		nodes = append(nodes, fileNode{file: f, dirs: dirSegments(f.Path)})
+	}
+	return nodes
+}
+
+func dirSegments(path string) []string {
+	segments := strings.Split(path, "/")
+	if len(segments) <= 1 {
+		return nil
+	}
+	return segments[1 : len(segments)-1]
+}
+
+// isOverride reports whether a file lives inside a falcon override layer,
+// as opposed to being a chart base file.
+func (n fileNode) isOverride() bool {
+	return len(n.dirs) > 0 && n.dirs[0] == "falcon"
+}
+
+// levelKey groups files that sit at the same hierarchical level, e.g.
+// chart1/values.yaml and chart1/default.yaml share the level key "".
+func (n fileNode) levelKey() string {
+	return strings.Join(n.dirs, "/")
+}
+
+// ignores reports whether key has an inline directive suppressing rule.
+func (n fileNode) ignores(key, rule string) bool {
+	set, ok := n.file.Ignores[leafKey(key)]
+	if !ok {
+		return false
+	}
+	return set.Ignores(rule)
+}
+
+func leafKey(key string) string {
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		return key[i+1:]
+	}
+	return key
+}
+
+func (n fileNode) fileName() string {
+	segments := strings.Split(n.file.Path, "/")
+	return segments[len(segments)-1]
+}
+
+func (n fileNode) chart() string {
+	return strings.SplitN(n.file.Path, "/", 2)[0]
+}
+
+// directParent finds the nearest ancestor file in the override hierarchy,
+// walking up from the most specific override layer towards the chart base
+// (e.g. falcon/env/dev -> falcon/env -> falcon -> base) and returning the
+// first one that actually exists in the tree.
+func directParent(nodes []fileNode, n fileNode) (fileNode, bool) {
+	if !n.isOverride() {
+		return fileNode{}, false
+	}
+	chart, filename := n.chart(), n.fileName()
+	for i := len(n.dirs) - 1; i >= 0; i-- {
+		candidatePath := chart + "/" + filename
+		if i > 0 {
+			candidatePath = chart + "/" + strings.Join(n.dirs[:i], "/") + "/" + filename
+		}
+		for _, cand := range nodes {
+			if cand.file.Path == candidatePath {
+				return cand, true
+			}
+		}
+	}
+	return fileNode{}, false
+}
+
+// Run evaluates every enabled built-in rule against tree using cfg and
+// returns the deterministic set of warnings, sorted for stable comparison.
+func Run(tree ConfigTree, cfg Config) []ExpectedWarning {
+	nodes := fileNodes(tree)
+
+	var warnings []ExpectedWarning
+	if cfg.Enabled(RuleDuplicateKey) {
+		warnings = append(warnings, duplicateKeyWarnings(nodes, cfg)...)
+	}
+	if cfg.Enabled(RuleRedundantOverride) {
+		warnings = append(warnings, redundantOverrideWarnings(nodes, cfg)...)
+	}
+	if cfg.Enabled(RuleOverrideOnlyKey) {
+		warnings = append(warnings, overrideOnlyKeyWarnings(nodes, cfg)...)
+	}
+	if cfg.Enabled(RuleSensitiveValue) {
+		warnings = append(warnings, sensitiveValueWarnings(nodes, cfg)...)
+	}
+	for _, rule := range RegisteredRules() {
+		if cfg.Enabled(rule.Name()) {
+			warnings = append(warnings, rule.Check(tree, cfg)...)
+		}
+	}
+
+	sort.Slice(warnings, func(i, j int) bool {
+		if warnings[i].Path != warnings[j].Path {
+			return warnings[i].Path < warnings[j].Path
+		}
+		if warnings[i].Key != warnings[j].Key {
+			return warnings[i].Key < warnings[j].Key
+		}
+		return warnings[i].Message < warnings[j].Message
+	})
+	return warnings
+}
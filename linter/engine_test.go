@@ -0,0 +1,174 @@
+package linter
+
+import "testing"
+
+func TestRunDetectsDuplicateKey(t *testing.T) {
+	tree := ConfigTree{
+		Chart: "chart1",
+		Configs: []FileData{
+			{Path: "chart1/values.yaml", Values: map[string]interface{}{"replicaCount": float64(2)}},
+			{Path: "chart1/default.yaml", Values: map[string]interface{}{"replicaCount": float64(3)}},
+		},
+	}
+
+	warnings := Run(tree, DefaultConfig())
+	if !containsKey(warnings, "chart1/values.yaml", "replicaCount") {
+		t.Fatalf("expected duplicate key warning for replicaCount, got %+v", warnings)
+	}
+}
+
+func TestRunDetectsRedundantOverride(t *testing.T) {
+	tree := ConfigTree{
+		Chart: "chart1",
+		Configs: []FileData{
+			{Path: "chart1/values.yaml", Values: map[string]interface{}{"image": "nginx"}},
+			{Path: "chart1/falcon/env/dev/values.yaml", Values: map[string]interface{}{"image": "nginx"}},
+		},
+	}
+
+	warnings := Run(tree, DefaultConfig())
+	if !containsKey(warnings, "chart1/falcon/env/dev/values.yaml", "image") {
+		t.Fatalf("expected redundant override warning for image, got %+v", warnings)
+	}
+}
+
+func TestRunDetectsOverrideOnlyKey(t *testing.T) {
+	tree := ConfigTree{
+		Chart: "chart1",
+		Configs: []FileData{
+			{Path: "chart1/values.yaml", Values: map[string]interface{}{"image": "nginx"}},
+			{Path: "chart1/falcon/env/dev/values.yaml", Values: map[string]interface{}{"debug": true}},
+		},
+	}
+
+	warnings := Run(tree, DefaultConfig())
+	if !containsKey(warnings, "chart1/falcon/env/dev/values.yaml", "debug") {
+		t.Fatalf("expected override-only key warning for debug, got %+v", warnings)
+	}
+}
+
+func TestRunDetectsSensitiveValue(t *testing.T) {
+	tree := ConfigTree{
+		Chart: "chart1",
+		Configs: []FileData{
+			{Path: "chart1/values.yaml", Values: map[string]interface{}{"region": "us-west-1"}},
+		},
+	}
+
+	warnings := Run(tree, DefaultConfig())
+	if !containsKey(warnings, "chart1/values.yaml", "region") {
+		t.Fatalf("expected sensitive value warning for region, got %+v", warnings)
+	}
+}
+
+func TestRunHonorsIgnoreDirective(t *testing.T) {
+	tree := ConfigTree{
+		Chart: "chart1",
+		Configs: []FileData{
+			{Path: "chart1/values.yaml", Values: map[string]interface{}{"image": "nginx"}},
+			{
+				Path:    "chart1/falcon/env/dev/values.yaml",
+				Values:  map[string]interface{}{"image": "nginx"},
+				Ignores: map[string]IgnoreSet{"image": {RuleRedundantOverride: IgnoreDirective{Rule: RuleRedundantOverride}}},
+			},
+		},
+	}
+
+	warnings := Run(tree, DefaultConfig())
+	if containsKey(warnings, "chart1/falcon/env/dev/values.yaml", "image") {
+		t.Fatalf("expected redundant-override warning to be suppressed, got %+v", warnings)
+	}
+}
+
+func TestParseIgnoreDirectives(t *testing.T) {
+	raw := `image: nginx
+# linter:ignore redundant-override
+debug: true
+password: "hunter2" # linter:ignore sensitive-value reason="test fixture"
+`
+
+	directives := ParseIgnoreDirectives(raw)
+
+	if !directives["debug"].Ignores(RuleRedundantOverride) {
+		t.Fatalf("expected debug to ignore %s, got %+v", RuleRedundantOverride, directives)
+	}
+	if d := directives["password"][RuleSensitiveValue]; d.Reason != "test fixture" {
+		t.Fatalf("expected password's reason to be captured, got %+v", directives)
+	}
+	if len(directives["image"]) != 0 {
+		t.Fatalf("did not expect image to have any directives, got %+v", directives)
+	}
+}
+
+func TestRunRespectsDisabledRule(t *testing.T) {
+	tree := ConfigTree{
+		Chart: "chart1",
+		Configs: []FileData{
+			{Path: "chart1/values.yaml", Values: map[string]interface{}{"region": "us-west-1"}},
+		},
+	}
+	cfg := DefaultConfig()
+	cfg.Rules[RuleSensitiveValue] = RuleSettings{Enabled: boolPtr(false)}
+
+	warnings := Run(tree, cfg)
+	if containsKey(warnings, "chart1/values.yaml", "region") {
+		t.Fatalf("expected sensitive-value rule to be disabled, got %+v", warnings)
+	}
+}
+
+func TestRunRespectsRegionAllowlist(t *testing.T) {
+	tree := ConfigTree{
+		Chart: "chart1",
+		Configs: []FileData{
+			{Path: "chart1/values.yaml", Values: map[string]interface{}{"region": "us-west-1"}},
+		},
+	}
+	cfg := DefaultConfig()
+	cfg.SensitiveValue.RegionAllowlist = []string{"us-west-1"}
+
+	warnings := Run(tree, cfg)
+	if containsKey(warnings, "chart1/values.yaml", "region") {
+		t.Fatalf("expected allowlisted region to not be flagged, got %+v", warnings)
+	}
+}
+
+func TestRunIncludesRegisteredRules(t *testing.T) {
+	tree := ConfigTree{
+		Chart: "chart1",
+		Configs: []FileData{
+			{Path: "chart1/values.yaml", Values: map[string]interface{}{
+				"image": "myapp:latest",
+				"resources": map[string]interface{}{
+					"requests": map[string]interface{}{"cpu": "100m"},
+				},
+			}},
+		},
+	}
+
+	warnings := Run(tree, DefaultConfig())
+	if !containsKey(warnings, "chart1/values.yaml", "image") {
+		t.Fatalf("expected image-tag-latest warning, got %+v", warnings)
+	}
+	if !containsKey(warnings, "chart1/values.yaml", "resources") {
+		t.Fatalf("expected resource-limits-missing warning, got %+v", warnings)
+	}
+}
+
+func TestRegisteredRulesIncludeBuiltinExamples(t *testing.T) {
+	var names []string
+	for _, r := range RegisteredRules() {
+		names = append(names, r.Name())
+	}
+	if !contains(names, RuleImageTagLatest) || !contains(names, RuleResourceLimitsMissing) {
+		t.Fatalf("expected built-in example rules to be registered, got %v", names)
+	}
+}
+
+func containsKey(warnings []ExpectedWarning, path, key string) bool {
+	for _, w := range warnings {
+		if w.Path == path && w.Key == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,21 @@
+package linter
+
+// IgnoreDirective is a single "# linter:ignore <rule>" annotation attached
+// to a key in a values file, e.g.:
+//
+//	password: "hunter2" # linter:ignore sensitive-value reason="test fixture"
+type IgnoreDirective struct {
+	Rule   string `json:"rule"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// IgnoreSet is the set of directives attached to one key, keyed by rule
+// name so a single key can suppress more than one rule.
+type IgnoreSet map[string]IgnoreDirective
+
+// Ignores reports whether rule is suppressed for the key this set belongs
+// to.
+func (s IgnoreSet) Ignores(rule string) bool {
+	_, ok := s[rule]
+	return ok
+}
@@ -0,0 +1,78 @@
+package linter
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	keyLinePattern   = regexp.MustCompile(`^\s*([A-Za-z0-9_.-]+):`)
+	directivePattern = regexp.MustCompile(`#\s*linter:ignore\s+([a-z-]+)(?:\s+reason="([^"]*)")?`)
+)
+
+// ParseIgnoreDirectives scans raw YAML source for "# linter:ignore <rule>"
+// comments and returns the directives they attach, keyed by the leaf key
+// name they annotate. A directive may sit on the same line as the key it
+// annotates, or on its own line immediately above it:
+//
+//	# linter:ignore redundant-override
+//	image: nginx
+//	password: "hunter2" # linter:ignore sensitive-value reason="test fixture"
+//
+// This is what the loader that builds FileData.Values calls to populate
+// FileData.Ignores alongside it.
+func ParseIgnoreDirectives(raw string) map[string]IgnoreSet {
+	directives := map[string]IgnoreSet{}
+	var pending []IgnoreDirective
+
+	for _, line := range strings.Split(raw, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "#") {
+			if m := directivePattern.FindStringSubmatch(trimmed); m != nil {
+				pending = append(pending, IgnoreDirective{Rule: m[1], Reason: m[2]})
+			}
+			continue
+		}
+
+		key := keyLinePattern.FindStringSubmatch(line)
+		if key == nil {
+			continue
+		}
+
+		found := pending
+		pending = nil
+		if m := directivePattern.FindStringSubmatch(line); m != nil {
+			found = append(found, IgnoreDirective{Rule: m[1], Reason: m[2]})
+		}
+		if len(found) == 0 {
+			continue
+		}
+
+		set := directives[key[1]]
+		if set == nil {
+			set = IgnoreSet{}
+		}
+		for _, d := range found {
+			set[d.Rule] = d
+		}
+		directives[key[1]] = set
+	}
+	return directives
+}
+
+// ResolveIgnores populates each file's Ignores from its Raw YAML source by
+// running ParseIgnoreDirectives over it. A file with a hand-authored
+// Ignores map (or no Raw source) is left untouched, so a fixture can still
+// set Ignores directly instead of via inline comments.
+func ResolveIgnores(tree ConfigTree) ConfigTree {
+	resolved := make([]FileData, len(tree.Configs))
+	for i, f := range tree.Configs {
+		if f.Raw != "" && len(f.Ignores) == 0 {
+			f.Ignores = ParseIgnoreDirectives(f.Raw)
+		}
+		resolved[i] = f
+	}
+	tree.Configs = resolved
+	return tree
+}
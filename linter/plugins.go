@@ -0,0 +1,104 @@
+package linter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// These two rules exist as a template for integrators: they're ordinary
+// Rule implementations registered through the same Register call an
+// external package would use, not special-cased by the engine.
+const (
+	RuleImageTagLatest        = "image-tag-latest"
+	RuleResourceLimitsMissing = "resource-limits-missing"
+)
+
+func init() {
+	Register(imageTagLatestRule{})
+	Register(resourceLimitsMissingRule{})
+}
+
+type imageTagLatestRule struct{}
+
+func (imageTagLatestRule) Name() string { return RuleImageTagLatest }
+
+func (imageTagLatestRule) Description() string {
+	return `Image Tag Must Not Be "latest": an "image" string or a "tag" key resolves to "latest", which makes deployments non-reproducible.`
+}
+
+func (r imageTagLatestRule) Check(tree ConfigTree, cfg Config) []ExpectedWarning {
+	severity := cfg.Severity(r.Name())
+	var warnings []ExpectedWarning
+	for _, f := range tree.Configs {
+		walkValues(f.Values, "", func(key string, value interface{}) {
+			str, ok := value.(string)
+			if !ok || f.Ignores[leafKey(key)].Ignores(r.Name()) {
+				return
+			}
+			leaf := leafKey(key)
+			latestTag := leaf == "tag" && str == "latest"
+			inlineLatestImage := leaf == "image" && strings.HasSuffix(str, ":latest")
+			if !latestTag && !inlineLatestImage {
+				return
+			}
+			warnings = append(warnings, ExpectedWarning{
+				Path:     f.Path,
+				Key:      key,
+				Message:  fmt.Sprintf("key %q pins an image to the mutable \"latest\" tag", key),
+				Severity: severity,
+			})
+		})
+	}
+	return warnings
+}
+
+type resourceLimitsMissingRule struct{}
+
+func (resourceLimitsMissingRule) Name() string { return RuleResourceLimitsMissing }
+
+func (resourceLimitsMissingRule) Description() string {
+	return `Resource Limits Missing: a "resources" block sets requests but has no matching limits, letting a pod consume unbounded CPU/memory.`
+}
+
+func (r resourceLimitsMissingRule) Check(tree ConfigTree, cfg Config) []ExpectedWarning {
+	severity := cfg.Severity(r.Name())
+	var warnings []ExpectedWarning
+	for _, f := range tree.Configs {
+		walkResourceBlocks(f.Values, "", func(key string, resources map[string]interface{}) {
+			if f.Ignores[leafKey(key)].Ignores(r.Name()) {
+				return
+			}
+			_, hasRequests := resources["requests"]
+			_, hasLimits := resources["limits"]
+			if hasRequests && !hasLimits {
+				warnings = append(warnings, ExpectedWarning{
+					Path:     f.Path,
+					Key:      key,
+					Message:  fmt.Sprintf("%q sets requests but no limits", key),
+					Severity: severity,
+				})
+			}
+		})
+	}
+	return warnings
+}
+
+// walkResourceBlocks finds every map named "resources" in a (possibly
+// nested) values tree and invokes visit with its dotted key path.
+func walkResourceBlocks(values map[string]interface{}, prefix string, visit func(key string, resources map[string]interface{})) {
+	for key, value := range values {
+		full := key
+		if prefix != "" {
+			full = prefix + "." + key
+		}
+		nested, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if key == "resources" {
+			visit(full, nested)
+			continue
+		}
+		walkResourceBlocks(nested, full, visit)
+	}
+}
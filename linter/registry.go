@@ -0,0 +1,41 @@
+package linter
+
+import "sync"
+
+// Rule lets external packages add their own checks to the rule engine
+// alongside the four built-in ones, so TestLinterAccuracy can discover and
+// score them the same way.
+type Rule interface {
+	// Name is the rule's identifier, used in Config.Rules, ignore
+	// directives and ExpectedWarning.Severity lookups (e.g. "image-tag-latest").
+	Name() string
+	// Description is a one-line, human-readable explanation of what the
+	// rule flags, included in the LLM prompt so the model knows to look
+	// for it.
+	Description() string
+	// Check evaluates the rule against tree and returns its findings.
+	Check(tree ConfigTree, cfg Config) []ExpectedWarning
+}
+
+var registry struct {
+	mu    sync.Mutex
+	rules []Rule
+}
+
+// Register adds rule to the set evaluated by Run and described in the LLM
+// prompt. It's typically called from an init() function.
+func Register(rule Rule) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.rules = append(registry.rules, rule)
+}
+
+// RegisteredRules returns every rule registered so far, in registration
+// order.
+func RegisteredRules() []Rule {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	rules := make([]Rule, len(registry.rules))
+	copy(rules, registry.rules)
+	return rules
+}
@@ -0,0 +1,223 @@
+package linter
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// duplicateKeyWarnings flags keys that are defined in more than one file at
+// the same hierarchical level, e.g. chart1/values.yaml and
+// chart1/default.yaml both setting "replicaCount".
+func duplicateKeyWarnings(nodes []fileNode, cfg Config) []ExpectedWarning {
+	severity := cfg.Severity(RuleDuplicateKey)
+	levels := map[string][]fileNode{}
+	byPath := map[string]fileNode{}
+	for _, n := range nodes {
+		levels[n.levelKey()] = append(levels[n.levelKey()], n)
+		byPath[n.file.Path] = n
+	}
+
+	var warnings []ExpectedWarning
+	for _, level := range levels {
+		if len(level) < 2 {
+			continue
+		}
+		pathsByKey := map[string][]string{}
+		for _, n := range level {
+			for key := range n.file.Values {
+				pathsByKey[key] = append(pathsByKey[key], n.file.Path)
+			}
+		}
+		for key, paths := range pathsByKey {
+			if len(paths) < 2 {
+				continue
+			}
+			sort.Strings(paths)
+			for _, path := range paths {
+				if byPath[path].ignores(key, RuleDuplicateKey) {
+					continue
+				}
+				warnings = append(warnings, ExpectedWarning{
+					Path:     path,
+					Key:      key,
+					Message:  fmt.Sprintf("key %q is also defined in %s", key, strings.Join(otherPaths(paths, path), ", ")),
+					Severity: severity,
+				})
+			}
+		}
+	}
+	return warnings
+}
+
+func otherPaths(paths []string, exclude string) []string {
+	var out []string
+	for _, p := range paths {
+		if p != exclude {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// redundantOverrideWarnings flags override keys that set the exact same
+// value as their direct parent, which is a no-op override.
+func redundantOverrideWarnings(nodes []fileNode, cfg Config) []ExpectedWarning {
+	severity := cfg.Severity(RuleRedundantOverride)
+	var warnings []ExpectedWarning
+	for _, n := range nodes {
+		parent, ok := directParent(nodes, n)
+		if !ok {
+			continue
+		}
+		for key, value := range n.file.Values {
+			if n.ignores(key, RuleRedundantOverride) {
+				continue
+			}
+			parentValue, exists := parent.file.Values[key]
+			if exists && reflect.DeepEqual(value, parentValue) {
+				warnings = append(warnings, ExpectedWarning{
+					Path:     n.file.Path,
+					Key:      key,
+					Message:  fmt.Sprintf("key %q redundantly overrides %s with the same value", key, parent.file.Path),
+					Severity: severity,
+				})
+			}
+		}
+	}
+	return warnings
+}
+
+// overrideOnlyKeyWarnings flags keys that only exist in an override layer
+// and have no corresponding key in the direct parent configuration.
+func overrideOnlyKeyWarnings(nodes []fileNode, cfg Config) []ExpectedWarning {
+	severity := cfg.Severity(RuleOverrideOnlyKey)
+	var warnings []ExpectedWarning
+	for _, n := range nodes {
+		parent, ok := directParent(nodes, n)
+		if !ok {
+			continue
+		}
+		for key := range n.file.Values {
+			if _, exists := parent.file.Values[key]; exists {
+				continue
+			}
+			if n.ignores(key, RuleOverrideOnlyKey) {
+				continue
+			}
+			warnings = append(warnings, ExpectedWarning{
+				Path:     n.file.Path,
+				Key:      key,
+				Message:  fmt.Sprintf("key %q is introduced in an override layer and has no parent in %s", key, parent.file.Path),
+				Severity: severity,
+			})
+		}
+	}
+	return warnings
+}
+
+var (
+	awsRegionPattern = regexp.MustCompile(`^(us|eu|ap|sa|ca|me|af)-[a-z]+-\d$`)
+	accountIDPattern = regexp.MustCompile(`^\d{12}$`)
+	arnPattern       = regexp.MustCompile(`^arn:`)
+	secretKeywords   = []string{"key", "token", "password", "credential", "secret"}
+)
+
+// sensitiveValueWarnings flags hardcoded AWS regions, account IDs, ARNs,
+// values matching cfg's custom patterns, and values stored under keys that
+// look like secrets.
+func sensitiveValueWarnings(nodes []fileNode, cfg Config) []ExpectedWarning {
+	severity := cfg.Severity(RuleSensitiveValue)
+	extra := compilePatterns(cfg.SensitiveValue.ExtraPatterns)
+
+	var warnings []ExpectedWarning
+	for _, n := range nodes {
+		walkValues(n.file.Values, "", func(key string, value interface{}) {
+			str, ok := value.(string)
+			if num, isNum := value.(float64); isNum {
+				// An unquoted account ID decodes as a number, not a string.
+				if s := strconv.FormatFloat(num, 'f', -1, 64); accountIDPattern.MatchString(s) {
+					str, ok = s, true
+				}
+			}
+			if !ok {
+				return
+			}
+			if n.ignores(key, RuleSensitiveValue) {
+				return
+			}
+			if reason, sensitive := classifySensitive(cfg, extra, key, str); sensitive {
+				warnings = append(warnings, ExpectedWarning{
+					Path:     n.file.Path,
+					Key:      key,
+					Message:  fmt.Sprintf("value for %q looks like a hardcoded %s", key, reason),
+					Severity: severity,
+				})
+			}
+		})
+	}
+	return warnings
+}
+
+func compilePatterns(patterns []string) []*regexp.Regexp {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		if re, err := regexp.Compile(p); err == nil {
+			compiled = append(compiled, re)
+		}
+	}
+	return compiled
+}
+
+func classifySensitive(cfg Config, extra []*regexp.Regexp, key, value string) (reason string, sensitive bool) {
+	switch {
+	case arnPattern.MatchString(value):
+		return "ARN", true
+	case accountIDPattern.MatchString(value):
+		return "account ID", true
+	case awsRegionPattern.MatchString(value):
+		if !contains(cfg.SensitiveValue.RegionAllowlist, value) {
+			return "AWS region", true
+		}
+	}
+	for _, re := range extra {
+		if re.MatchString(value) {
+			return "custom-pattern value", true
+		}
+	}
+	lowerKey := strings.ToLower(key)
+	for _, kw := range append(secretKeywords, cfg.SensitiveValue.ExtraSecretKeywords...) {
+		if strings.Contains(lowerKey, strings.ToLower(kw)) {
+			return "secret", true
+		}
+	}
+	return "", false
+}
+
+func contains(list []string, target string) bool {
+	for _, v := range list {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
+
+// walkValues recursively visits every leaf in a (possibly nested) values
+// map, reporting dotted key paths like "database.password".
+func walkValues(values map[string]interface{}, prefix string, visit func(key string, value interface{})) {
+	for key, value := range values {
+		full := key
+		if prefix != "" {
+			full = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			walkValues(nested, full, visit)
+			continue
+		}
+		visit(full, value)
+	}
+}
@@ -0,0 +1,27 @@
+package linter
+
+// WarningsSchema is the JSON Schema the LLM's response must satisfy when
+// asked to emit warnings as structured JSON (see extractStructuredWarnings
+// in main_test.go). It describes the same wire shape as the
+// structuredWarning type there, which is decoded after validation.
+const WarningsSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "required": ["warnings"],
+  "properties": {
+    "warnings": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "required": ["path", "key", "value", "rule", "message"],
+        "properties": {
+          "path":    {"type": "string"},
+          "key":     {"type": "string"},
+          "value":   {"type": "string"},
+          "rule":    {"type": "string"},
+          "message": {"type": "string"}
+        }
+      }
+    }
+  }
+}`
@@ -0,0 +1,42 @@
+// Package linter implements a deterministic, rule-based linter for
+// hierarchical Helm-style values.yaml configuration trees. It mirrors the
+// checks described to the LLM in the TestLinterAccuracy prompt so the two
+// can be compared against each other instead of against hand-written
+// fixtures.
+package linter
+
+// FileData is a single configuration file somewhere in a chart's override
+// hierarchy, along with the key/value pairs it defines.
+type FileData struct {
+	Path   string                 `json:"path"`
+	Values map[string]interface{} `json:"values"`
+
+	// Raw is the file's original YAML source, if the fixture supplied
+	// one. ResolveIgnores parses it for "# linter:ignore <rule>"
+	// comments so a fixture author can write the directive inline
+	// instead of hand-authoring Ignores.
+	Raw string `json:"raw,omitempty"`
+
+	// Ignores holds per-key "# linter:ignore <rule>" directives found
+	// in the file's source, keyed by the leaf key name they annotate.
+	// It runs alongside Values rather than inside it so a directive
+	// can be attached to a key regardless of its value's shape.
+	Ignores map[string]IgnoreSet `json:"ignores,omitempty"`
+}
+
+// ConfigTree is the full set of configuration files for one chart, as sent
+// to the LLM and walked by the rule engine.
+type ConfigTree struct {
+	Chart   string     `json:"chart"`
+	Configs []FileData `json:"configs"`
+}
+
+// ExpectedWarning is a single linter finding. Both the deterministic rule
+// engine and the LLM (after extraction) produce slices of these so they can
+// be scored against each other with scoreWarnings.
+type ExpectedWarning struct {
+	Path     string   `json:"path"`
+	Key      string   `json:"key"`
+	Message  string   `json:"message"`
+	Severity Severity `json:"severity,omitempty"`
+}
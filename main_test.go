@@ -3,6 +3,7 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"math"
 	"net/http"
@@ -11,28 +12,37 @@ import (
 	"sort"
 	"strings"
 	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+
+	"github.com/mnuthan1/llm-go-test/linter"
 )
 
-type FileData struct {
-	Path   string                 `json:"path"`
-	Values map[string]interface{} `json:"values"`
-}
+// legacyExtract falls back to the old regex-based extractWarnings for
+// models that don't reliably honor the "format": "json" request option.
+var legacyExtract = flag.Bool("legacy-extract", false, "use the regex-based warning extractor instead of the structured JSON protocol")
 
-type ConfigTree struct {
-	Chart   string     `json:"chart"`
-	Configs []FileData `json:"configs"`
-}
+var warningsSchema = mustCompileSchema("warnings.json", linter.WarningsSchema)
 
-type ExpectedWarning struct {
-	Path    string `json:"path"`
-	Key     string `json:"key"`
-	Message string `json:"message"`
+func mustCompileSchema(url, schema string) *jsonschema.Schema {
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(url, strings.NewReader(schema)); err != nil {
+		panic(err)
+	}
+	return compiler.MustCompile(url)
 }
 
+// FileData, ConfigTree and ExpectedWarning are shared with the linter
+// package so the deterministic rule engine and this test harness always
+// agree on the shape of a configuration tree.
+type FileData = linter.FileData
+type ConfigTree = linter.ConfigTree
+type ExpectedWarning = linter.ExpectedWarning
+
 type TestCase struct {
-	Name             string            `json:"name"`
-	Input            ConfigTree        `json:"input"`
-	ExpectedWarnings []ExpectedWarning `json:"expected_warnings"`
+	Name   string         `json:"name"`
+	Input  ConfigTree     `json:"input"`
+	Config *linter.Config `json:"config,omitempty"`
 }
 
 func TestLinterAccuracy(t *testing.T) {
@@ -41,6 +51,22 @@ func TestLinterAccuracy(t *testing.T) {
 		t.Fatalf("Failed to read test_cases directory: %v", err)
 	}
 
+	// linter.yaml is the project-wide rule configuration; a test case's
+	// own Config (if any) is layered on top of it, not on top of
+	// DefaultConfig(), so the fixture only has to state what it's
+	// overriding relative to what the repo actually ships.
+	baseCfg, err := linter.LoadConfig("linter.yaml")
+	if err != nil {
+		if !os.IsNotExist(err) {
+			t.Fatalf("Failed to load linter.yaml: %v", err)
+		}
+		baseCfg = linter.DefaultConfig()
+	}
+
+	// Shared for the whole test run so repeated messages across test
+	// cases only pay for one embedding call each.
+	embeddingCache := map[string][]float64{}
+
 	for _, file := range files {
 		if !strings.HasSuffix(file.Name(), ".json") {
 			continue
@@ -56,6 +82,12 @@ func TestLinterAccuracy(t *testing.T) {
 			if err := json.Unmarshal(data, &tc); err != nil {
 				t.Fatalf("Failed to unmarshal test case: %v", err)
 			}
+			tc.Input = linter.ResolveIgnores(tc.Input)
+
+			cfg := baseCfg
+			if tc.Config != nil {
+				cfg = linter.MergeOverrides(cfg, *tc.Config)
+			}
 
 			prompt := fmt.Sprintf(`You are a YAML configuration linter that analyzes hierarchical configuration trees.
 
@@ -63,33 +95,29 @@ func TestLinterAccuracy(t *testing.T) {
 			Configuration files are organized hierarchically, following a structure like chart1/falcon/env/dev/values.yaml.
 			Chart Base: The first path segment (e.g., chart1/) defines the chart base, files in base folder are not overrides.
 			Override Layers: Any subfolders named falcon or deeper within a path (e.g., chart1/falcon/...) represent override layers.
-			Parent-Child Relationship: The file path hierarchy dictates parent-child relationships for override detection. For instance, chart1/values.yaml is the parent of chart1/falcon/env/dev/values.yaml. 
-			
+			Parent-Child Relationship: The file path hierarchy dictates parent-child relationships for override detection. For instance, chart1/values.yaml is the parent of chart1/falcon/env/dev/values.yaml.
+
 			Linter Rules:
-			1. Identify and report the following issues:
-			2. Duplicate Keys (Same Level): A key is defined in multiple files at the same hierarchical level (e.g., chart1/values.yaml and chart1/default.yaml).
-			3. Redundant Override: An override file (within a falcon layer) sets a key to the exact same value as its parent configuration file. (Note: Differing values are valid overrides and should not be flagged).
-			4. Override-Only Key: A key is introduced only within an override layer (falcon/...) and does not exist in its direct parent configuration file.
-			5. Hardcoded Sensitive Values: The configuration contains values matching patterns for sensitive data:
-			   - AWS regions (e.g., us-west-1, ap-southeast-2)
-			   - Account IDs (12-digit numbers)
-			   - ARNs (starting with arn:)
-			   - Common secret identifiers (e.g., key, token, password, credential) 
-			
-			Output Format:
-			For each detected issue, provide:
-			- File Path
-			- Key
-			- Value
-			- Warning Type & Suggestion
-			
+			Identify and report the following issues. Each rule below is tagged with
+			the severity ("error"/"warning"/"info") it must be reported at; disabled
+			rules are omitted entirely and must not be reported.
+%s
+			Ignore Directives:
+			A key's source line, or the line immediately above it, may carry an inline
+			comment of the form "# linter:ignore <rule>" (optionally with
+			reason="..."), e.g. "password: \"hunter2\" # linter:ignore sensitive-value
+			reason=\"test fixture\"". A key carrying such a directive must not be
+			reported for that rule, even if it would otherwise match.
+
+			%s
+
 			Constraints:
 			Analyze only the provided configuration data. Do not infer or invent keys, values, or file paths.
-			Generate warnings only for keys explicitly present in the input. 
-			
+			Generate warnings only for keys explicitly present in the input.
+
 			Now analyze this configuration tree:
-			
-			%s`, marshalTree(tc.Input))
+
+			%s`, buildRuleSection(cfg), outputFormatSection(cfg), marshalTree(tc.Input))
 
 			reqBody := map[string]interface{}{
 				"model":       "mistral",
@@ -97,6 +125,9 @@ func TestLinterAccuracy(t *testing.T) {
 				"stream":      false,
 				"temperature": 0,
 			}
+			if !*legacyExtract {
+				reqBody["format"] = "json"
+			}
 
 			reqBytes, _ := json.Marshal(reqBody)
 			resp, err := http.Post("http://localhost:11434/api/generate", "application/json", bytes.NewBuffer(reqBytes))
@@ -111,12 +142,27 @@ func TestLinterAccuracy(t *testing.T) {
 			}
 
 			text := result["response"].(string)
-			extracted := extractWarnings(text)
-			precision, recall := scoreWarnings(tc.ExpectedWarnings, extracted)
+
+			var extracted []ExpectedWarning
+			if *legacyExtract {
+				extracted = extractWarnings(text)
+			} else {
+				extracted, err = extractStructuredWarnings(text, cfg)
+				if err != nil {
+					t.Fatalf("Failed to extract warnings from %s: %v", tc.Name, err)
+				}
+			}
+
+			// The deterministic rule engine is the ground truth: it
+			// implements the same rules described in the prompt above
+			// (the built-ins plus every registered rule), so the LLM is scored against it instead of a
+			// hand-written (and easily stale) fixture.
+			ground := linter.Run(tc.Input, cfg)
+			precision, recall := scoreWarnings(ground, extracted, cfg, embeddingCache)
 			t.Logf("Precision = %.2f, Recall = %.2f", precision, recall)
 
 			if precision < 1.0 || recall < 1.0 {
-				t.Errorf("Failed test case %s: extracted = %+v", tc.Name, extracted)
+				t.Errorf("Failed test case %s: extracted = %+v, ground truth = %+v", tc.Name, extracted, ground)
 			}
 		})
 	}
@@ -127,6 +173,139 @@ func marshalTree(tree ConfigTree) string {
 	return string(bytes)
 }
 
+// promptRules lists every rule the prompt should mention, in the order
+// they're numbered: the 4 built-ins followed by whatever's registered
+// through linter.RegisteredRules(). buildRuleSection and outputFormatSection
+// both build on this so a plugin rule is never described to the model
+// without also appearing in the output-format enum, or vice versa.
+func promptRules(cfg linter.Config) []struct {
+	name string
+	desc string
+} {
+	rules := []struct {
+		name string
+		desc string
+	}{
+		{linter.RuleDuplicateKey, "Duplicate Keys (Same Level): A key is defined in multiple files at the same hierarchical level (e.g., chart1/values.yaml and chart1/default.yaml)."},
+		{linter.RuleRedundantOverride, "Redundant Override: An override file (within a falcon layer) sets a key to the exact same value as its parent configuration file. (Note: Differing values are valid overrides and should not be flagged)."},
+		{linter.RuleOverrideOnlyKey, "Override-Only Key: A key is introduced only within an override layer (falcon/...) and does not exist in its direct parent configuration file."},
+		{linter.RuleSensitiveValue, sensitiveValueDescription(cfg)},
+	}
+	for _, rule := range linter.RegisteredRules() {
+		rules = append(rules, struct {
+			name string
+			desc string
+		}{rule.Name(), rule.Description()})
+	}
+	return rules
+}
+
+// buildRuleSection renders the "Linter Rules" portion of the prompt from
+// cfg, so the LLM is only ever told about rules the rule engine itself
+// would evaluate, at the severity the rule engine would report them at.
+func buildRuleSection(cfg linter.Config) string {
+	var b strings.Builder
+	n := 1
+	for _, rule := range promptRules(cfg) {
+		if !cfg.Enabled(rule.name) {
+			continue
+		}
+		fmt.Fprintf(&b, "\t\t\t%d. [%s, severity=%s] %s\n", n, rule.name, cfg.Severity(rule.name), rule.desc)
+		n++
+	}
+	return b.String()
+}
+
+// outputFormatSection tells the model how to format its findings. The
+// structured JSON protocol is the default; --legacy-extract asks for the
+// older free-text format instead, for models that don't honor Ollama's
+// "format": "json" option. The rule enum in the structured case is built
+// from the same enabled-rule list buildRuleSection uses, so a registered
+// plugin rule is always a valid "rule" value too.
+func outputFormatSection(cfg linter.Config) string {
+	if *legacyExtract {
+		return "\t\t\tOutput Format:\n" +
+			"\t\t\tFor each detected issue, provide:\n" +
+			"\t\t\t- File Path\n" +
+			"\t\t\t- Key\n" +
+			"\t\t\t- Value\n" +
+			"\t\t\t- Warning Type & Suggestion"
+	}
+	var names []string
+	for _, rule := range promptRules(cfg) {
+		if !cfg.Enabled(rule.name) {
+			continue
+		}
+		names = append(names, rule.name)
+	}
+	return "\t\t\tOutput Format:\n" +
+		"\t\t\tRespond with a single JSON object and nothing else: " +
+		`{"warnings":[{"path":"...","key":"...","value":"...","rule":"...","message":"..."}]}` + "\n" +
+		"\t\t\trule must be one of: " + strings.Join(names, ", ") + "."
+}
+
+func sensitiveValueDescription(cfg linter.Config) string {
+	desc := "Hardcoded Sensitive Values: The configuration contains values matching patterns for sensitive data:\n" +
+		"\t\t\t   - AWS regions (e.g., us-west-1, ap-southeast-2)\n" +
+		"\t\t\t   - Account IDs (12-digit numbers)\n" +
+		"\t\t\t   - ARNs (starting with arn:)\n" +
+		"\t\t\t   - Common secret identifiers (e.g., key, token, password, credential)"
+	if len(cfg.SensitiveValue.RegionAllowlist) > 0 {
+		desc += fmt.Sprintf("\n\t\t\t   - These regions are allowlisted and must not be flagged: %s", strings.Join(cfg.SensitiveValue.RegionAllowlist, ", "))
+	}
+	if len(cfg.SensitiveValue.ExtraSecretKeywords) > 0 {
+		desc += fmt.Sprintf("\n\t\t\t   - Additional secret keywords: %s", strings.Join(cfg.SensitiveValue.ExtraSecretKeywords, ", "))
+	}
+	if len(cfg.SensitiveValue.ExtraPatterns) > 0 {
+		desc += fmt.Sprintf("\n\t\t\t   - Additional sensitive-value patterns: %s", strings.Join(cfg.SensitiveValue.ExtraPatterns, ", "))
+	}
+	return desc
+}
+
+// structuredWarning is the wire shape of a single warning in the JSON
+// protocol: {"path":..,"key":..,"value":..,"rule":..,"message":..}.
+type structuredWarning struct {
+	Path    string `json:"path"`
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+type structuredResponse struct {
+	Warnings []structuredWarning `json:"warnings"`
+}
+
+// extractStructuredWarnings validates text against linter.WarningsSchema
+// before decoding it, so a response that isn't valid JSON (e.g. wrapped in
+// markdown fencing) or lacks a required field surfaces as a clear test
+// failure instead of an empty slice of warnings. Extra fields are allowed.
+func extractStructuredWarnings(text string, cfg linter.Config) ([]ExpectedWarning, error) {
+	var raw interface{}
+	if err := json.Unmarshal([]byte(text), &raw); err != nil {
+		return nil, fmt.Errorf("response is not valid JSON: %w", err)
+	}
+	if err := warningsSchema.Validate(raw); err != nil {
+		return nil, fmt.Errorf("response does not match the warnings schema: %w", err)
+	}
+
+	var parsed structuredResponse
+	if err := json.Unmarshal([]byte(text), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode warnings: %w", err)
+	}
+
+	warnings := make([]ExpectedWarning, 0, len(parsed.Warnings))
+	for _, w := range parsed.Warnings {
+		warnings = append(warnings, ExpectedWarning{
+			Path:     w.Path,
+			Key:      w.Key,
+			Message:  w.Message,
+			Severity: cfg.Severity(w.Rule),
+		})
+	}
+	return warnings, nil
+}
+
 func extractWarnings(text string) []ExpectedWarning {
 	var warnings []ExpectedWarning
 	pattern := regexp.MustCompile(`(?m)- File Path: (.*?), Key: (.*?), Value: .*?\n.*?Suggestion: (.*?)\n`)
@@ -174,6 +353,57 @@ func comparePaths(p1, p2 string) bool {
 	return hasCommonElement(n1, n2)
 }
 
+// messageSimilarity scores how similar two warning messages are using
+// Ollama embeddings, which capture paraphrases that the bag-of-words
+// cosineSimilarity below rewards or punishes based on shared stopwords
+// alone. It falls back to cosineSimilarity when the embeddings endpoint
+// is unreachable, so offline CI still works.
+func messageSimilarity(a, b, model string, cache map[string][]float64) float64 {
+	va, ok := embedMessage(a, model, cache)
+	if !ok {
+		return cosineSimilarity(a, b)
+	}
+	vb, ok := embedMessage(b, model, cache)
+	if !ok {
+		return cosineSimilarity(a, b)
+	}
+	return dot(va, vb) / (magnitude(va)*magnitude(vb) + 1e-8)
+}
+
+// embedMessage returns the embedding vector for message, keyed in cache so
+// a message repeated across warnings or test cases is only embedded once
+// per test run.
+func embedMessage(message, model string, cache map[string][]float64) ([]float64, bool) {
+	cacheKey := model + "\x00" + message
+	if vec, ok := cache[cacheKey]; ok {
+		return vec, true
+	}
+
+	reqBytes, err := json.Marshal(map[string]interface{}{
+		"model":  model,
+		"prompt": message,
+	})
+	if err != nil {
+		return nil, false
+	}
+
+	resp, err := http.Post("http://localhost:11434/api/embeddings", "application/json", bytes.NewBuffer(reqBytes))
+	if err != nil {
+		return nil, false
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Embedding []float64 `json:"embedding"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil || len(result.Embedding) == 0 {
+		return nil, false
+	}
+
+	cache[cacheKey] = result.Embedding
+	return result.Embedding, true
+}
+
 func cosineSimilarity(a, b string) float64 {
 	awords := strings.Fields(strings.ToLower(a))
 	bwords := strings.Fields(strings.ToLower(b))
@@ -219,7 +449,8 @@ func magnitude(v []float64) float64 {
 	return math.Sqrt(sum)
 }
 
-func scoreWarnings(expected, actual []ExpectedWarning) (precision float64, recall float64) {
+func scoreWarnings(expected, actual []ExpectedWarning, cfg linter.Config, embeddingCache map[string][]float64) (precision float64, recall float64) {
+	weights := cfg.Scoring
 	match := 0
 	used := make([]bool, len(actual))
 	for _, e := range expected {
@@ -227,9 +458,12 @@ func scoreWarnings(expected, actual []ExpectedWarning) (precision float64, recal
 			if used[i] {
 				continue
 			}
+			if e.Severity != "" && a.Severity != "" && e.Severity != a.Severity {
+				continue
+			}
 			pathScore := 0.0
 			keyScore := 0.0
-			msgScore := cosineSimilarity(e.Message, a.Message)
+			msgScore := messageSimilarity(e.Message, a.Message, weights.EmbeddingModel, embeddingCache)
 
 			if comparePaths(e.Path, a.Path) {
 				pathScore = 1.0
@@ -238,8 +472,8 @@ func scoreWarnings(expected, actual []ExpectedWarning) (precision float64, recal
 				keyScore = 1.0
 			}
 
-			totalScore := 0.4*pathScore + 0.4*keyScore + 0.2*msgScore
-			if totalScore >= 0.75 {
+			totalScore := *weights.PathWeight*pathScore + *weights.KeyWeight*keyScore + *weights.MessageWeight*msgScore
+			if totalScore >= *weights.MatchThreshold {
 				match++
 				used[i] = true
 				break